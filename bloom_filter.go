@@ -11,11 +11,27 @@ import (
 	"strings"
 )
 
+// hashStrategy selects how a BloomFilter derives its k bit positions for an element.
+type hashStrategy int
+
+const (
+	// strategyRegistry derives positions from hashset, a set of Hashes drawn
+	// from the Hashes registry by total Weight. This is the default strategy,
+	// used by NewBloomFilter.
+	strategyRegistry hashStrategy = iota
+	// strategyDoubleHash derives positions via Kirsch-Mitzenmacher enhanced
+	// double hashing, letting the caller request k directly. See NewBloomFilterK.
+	strategyDoubleHash
+)
+
 // A Bloom Filter
 // as described in https://en.wikipedia.org/wiki/Bloom_filter
 type BloomFilter struct {
 	bitset  []byte // M bits
-	hashset []Hash // K Hash functions
+	hashset []Hash // K Hash functions, when strategy == strategyRegistry
+
+	strategy hashStrategy
+	k        int // number of bit positions per element, when strategy == strategyDoubleHash
 }
 
 const (
@@ -57,8 +73,12 @@ func NewBloomFilter(size int, hashWeight int) (f *BloomFilter, err error) {
 	return f, nil
 }
 
-// Returns the HashWeight. See NewBloomFilter for details
+// Returns the HashWeight. See NewBloomFilter for details.
+// For a BloomFilter constructed with NewBloomFilterK, this returns k.
 func (f *BloomFilter) HashWeight() (result int) {
+	if f.strategy == strategyDoubleHash {
+		return f.k
+	}
 	for _, h := range f.hashset {
 		result += h.Weight
 	}
@@ -72,11 +92,8 @@ func (f *BloomFilter) NumBits() int {
 
 // Adds an element to the set
 func (f *BloomFilter) Add(e []byte) {
-	for _, hash := range f.hashset {
-		for _, h := range hash.Func(e) {
-			h = f.wrap(h)
-			f.set(h)
-		}
+	for _, h := range f.positions(e) {
+		f.set(h)
 	}
 }
 
@@ -84,16 +101,27 @@ func (f *BloomFilter) Add(e []byte) {
 // Returns true if the element may, but is not necessarily, in the set.
 // Returns false if the element is not in the set.
 func (f *BloomFilter) Query(e []byte) bool {
-	for _, hash := range f.hashset {
-		for _, h := range hash.Func(e) {
-			h = f.wrap(h)
-			if !f.check(h) {
-				return false
-			}
+	for _, h := range f.positions(e) {
+		if !f.check(h) {
+			return false
 		}
 	}
 	return true
+}
 
+// positions returns the k bit positions, already wrapped into [0, NumBits()),
+// that e maps to under f's hashStrategy.
+func (f *BloomFilter) positions(e []byte) []uint32 {
+	if f.strategy == strategyDoubleHash {
+		return doubleHashPositions(e, f.k, uint64(f.NumBits()))
+	}
+	positions := make([]uint32, 0, f.HashWeight())
+	for _, hash := range f.hashset {
+		for _, h := range hash.Func(e) {
+			positions = append(positions, f.wrap(h))
+		}
+	}
+	return positions
 }
 
 func (f *BloomFilter) String() string {
@@ -103,13 +131,14 @@ func (f *BloomFilter) String() string {
 // Displays the bloom filter
 // unless `showBitset“ is specified, this is filtered from the output
 func (f *BloomFilter) Display(showBitset bool) string {
-	hashWeight := 0
-	hsNames := make([]string, len(f.hashset))
-	for i, h := range f.hashset {
-		hashWeight += h.Weight
-		hsNames[i] = h.Name
+	hs := "doubleHash"
+	if f.strategy == strategyRegistry {
+		hsNames := make([]string, len(f.hashset))
+		for i, h := range f.hashset {
+			hsNames[i] = h.Name
+		}
+		hs = strings.Join(hsNames, ",")
 	}
-	hs := strings.Join(hsNames, ",")
 	bs := "filtered"
 	if showBitset {
 		bs = hex.EncodeToString(f.bitset)
@@ -131,7 +160,9 @@ func (f *BloomFilter) check(h uint32) bool {
 }
 
 func (f *BloomFilter) wrap(n uint32) uint32 {
-	return n % uint32(len(f.bitset)*8)
+	// NumBits() may be exactly maxSize (2^32), which overflows uint32, so the
+	// modulus must be taken in uint64 rather than uint32(len(f.bitset)*8).
+	return uint32(uint64(n) % uint64(len(f.bitset)*8))
 }
 
 // Calculates the probability that query returns a false positive
@@ -78,6 +78,19 @@ func TestFilterErrors(t *testing.T) {
 	}
 }
 
+func TestBloomFilterAtMaxSize(t *testing.T) {
+	// size == maxSize is accepted by NewBloomFilter (only size > maxSize
+	// errors), so Add/Query must not panic on the resulting filter.
+	f, err := bloom_filter.NewBloomFilter(uint32max+1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f.Add([]byte("x"))
+	if !f.Query([]byte("x")) {
+		t.Error("expected Query to find an element that was Added")
+	}
+}
+
 func TestBloomFilter(t *testing.T) {
 	source, err := streams.NewRandomStringReader(5, 10)
 	if err != nil {
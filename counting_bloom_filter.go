@@ -0,0 +1,165 @@
+package bloom_filter
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	minCounterBits = 4
+	maxCounterBits = 8
+)
+
+var errInvalidCounterBits = fmt.Errorf("counterBits must be %d or %d", minCounterBits, maxCounterBits)
+
+// A Counting Bloom Filter.
+// Like BloomFilter, but backed by a slice of small saturating counters instead
+// of single bits, which allows elements to be removed as well as added.
+// See https://en.wikipedia.org/wiki/Counting_Bloom_filter
+type CountingBloomFilter struct {
+	counters    []byte // packed counters, counterBits wide each
+	numCounters int
+	counterBits int
+	hashset     []Hash // K Hash functions
+}
+
+// Constructs a CountingBloomFilter with the specified size, hashWeight, and counterBits
+//
+// size and hashWeight behave as in NewBloomFilter.
+//
+// counterBits is the width, in bits, of each counter (4 or 8). A counter saturates
+// at its max value (15 for 4-bit, 255 for 8-bit) rather than overflowing, and once
+// saturated is treated as "sticky" by Remove so that a later decrement can't
+// underflow a count that was actually higher than what the counter could represent.
+func NewCountingBloomFilter(size int, hashWeight int, counterBits int) (f *CountingBloomFilter, err error) {
+	if size < minSize {
+		return nil, errSizeTooSmall
+	}
+	if size > maxSize {
+		return nil, errSizeTooLarge
+	}
+	if hashWeight < minHashWeight {
+		return nil, errTooFewHashes
+	}
+	if counterBits != minCounterBits && counterBits != maxCounterBits {
+		return nil, errInvalidCounterBits
+	}
+	f = &CountingBloomFilter{numCounters: size, counterBits: counterBits}
+	if f.hashset, err = hashset(hashWeight); err != nil {
+		return nil, err
+	}
+	countersPerByte := 8 / counterBits
+	f.counters = make([]byte, int(math.Ceil(float64(size)/float64(countersPerByte))))
+	return f, nil
+}
+
+// Returns the HashWeight. See NewBloomFilter for details
+func (f *CountingBloomFilter) HashWeight() (result int) {
+	for _, h := range f.hashset {
+		result += h.Weight
+	}
+	return result
+}
+
+// Returns the number of counters used. This is proportional to the specified size during construction.
+func (f *CountingBloomFilter) NumCounters() int {
+	return f.numCounters
+}
+
+// Adds an element to the set, incrementing each targeted counter (saturating at
+// the max value for counterBits).
+func (f *CountingBloomFilter) Add(e []byte) {
+	max := f.maxCounter()
+	for _, hash := range f.hashset {
+		for _, h := range hash.Func(e) {
+			i := f.wrap(h)
+			if v := f.getCounter(i); v < max {
+				f.setCounter(i, v+1)
+			}
+		}
+	}
+}
+
+// Removes an element from the set, decrementing each targeted counter (saturating
+// at 0). A counter that has saturated at the max value is left untouched, since
+// its true count may exceed what the counter can represent.
+func (f *CountingBloomFilter) Remove(e []byte) {
+	max := f.maxCounter()
+	for _, hash := range f.hashset {
+		for _, h := range hash.Func(e) {
+			i := f.wrap(h)
+			v := f.getCounter(i)
+			if v == 0 || v == max {
+				continue
+			}
+			f.setCounter(i, v-1)
+		}
+	}
+}
+
+// Queries for an element in the set.
+// Returns true if the element may, but is not necessarily, in the set.
+// Returns false if the element is not in the set.
+func (f *CountingBloomFilter) Query(e []byte) bool {
+	for _, hash := range f.hashset {
+		for _, h := range hash.Func(e) {
+			i := f.wrap(h)
+			if f.getCounter(i) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (f *CountingBloomFilter) String() string {
+	return f.Display(false)
+}
+
+// Displays the counting bloom filter
+// unless `showCounters` is specified, this is filtered from the output
+func (f *CountingBloomFilter) Display(showCounters bool) string {
+	hsNames := make([]string, len(f.hashset))
+	for i, h := range f.hashset {
+		hsNames[i] = h.Name
+	}
+	hs := strings.Join(hsNames, ",")
+	cs := "filtered"
+	if showCounters {
+		cs = hex.EncodeToString(f.counters)
+	}
+	return fmt.Sprintf("CountingBloomFilter{numCounters=%d, counterBits=%d, hashWeight=%d, hashset=[%s], counters=[%s]}",
+		f.numCounters, f.counterBits, f.HashWeight(), hs, cs)
+}
+
+func (f *CountingBloomFilter) wrap(h uint32) uint32 {
+	// numCounters may be exactly maxSize (2^32), which overflows uint32, so the
+	// modulus must be taken in uint64 rather than uint32(f.numCounters).
+	return uint32(uint64(h) % uint64(f.numCounters))
+}
+
+func (f *CountingBloomFilter) maxCounter() uint8 {
+	return uint8(1<<uint(f.counterBits) - 1)
+}
+
+func (f *CountingBloomFilter) countersPerByte() uint32 {
+	return uint32(8 / f.counterBits)
+}
+
+func (f *CountingBloomFilter) getCounter(i uint32) uint8 {
+	perByte := f.countersPerByte()
+	byteIndex := i / perByte
+	pos := (i % perByte) * uint32(f.counterBits)
+	mask := f.maxCounter() << pos
+	return (f.counters[byteIndex] & mask) >> pos
+}
+
+func (f *CountingBloomFilter) setCounter(i uint32, v uint8) {
+	perByte := f.countersPerByte()
+	byteIndex := i / perByte
+	pos := (i % perByte) * uint32(f.counterBits)
+	mask := f.maxCounter() << pos
+	f.counters[byteIndex] = (f.counters[byteIndex] &^ mask) | ((v << pos) & mask)
+}
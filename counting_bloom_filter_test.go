@@ -0,0 +1,91 @@
+package bloom_filter_test
+
+import (
+	"testing"
+
+	"github.com/vdinovi/go/bloom_filter"
+)
+
+func TestCountingBloomFilterBasic(t *testing.T) {
+	f, err := bloom_filter.NewCountingBloomFilter(64, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{"abc", "def", "ghi"} {
+		b := []byte(w)
+		f.Add(b)
+		if !f.Query(b) {
+			t.Errorf("expected %q to be in set but was not: %s", w, f.Display(true))
+		}
+	}
+	for _, w := range []string{"jkl", "mno", "pqr"} {
+		b := []byte(w)
+		if f.Query(b) {
+			t.Errorf("didn't expect %q to be in set but was: %s", w, f.Display(true))
+		}
+	}
+}
+
+func TestCountingBloomFilterRemove(t *testing.T) {
+	f, err := bloom_filter.NewCountingBloomFilter(64, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := []byte("abc"), []byte("def")
+	f.Add(a)
+	f.Add(b)
+	if !f.Query(a) || !f.Query(b) {
+		t.Fatalf("expected both elements to be in set: %s", f.Display(true))
+	}
+
+	f.Remove(a)
+	if f.Query(a) {
+		t.Errorf("expected %q to be removed from set: %s", "abc", f.Display(true))
+	}
+	if !f.Query(b) {
+		t.Errorf("expected %q to remain in set: %s", "def", f.Display(true))
+	}
+}
+
+func TestCountingBloomFilterRemoveSaturatedIsSticky(t *testing.T) {
+	f, err := bloom_filter.NewCountingBloomFilter(64, 4, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := []byte("abc")
+	// 4-bit counters saturate at 15; adding 20 times should leave every
+	// targeted counter pinned at the max value.
+	for i := 0; i < 20; i += 1 {
+		f.Add(a)
+	}
+	f.Remove(a)
+	if !f.Query(a) {
+		t.Errorf("expected saturated counters to remain sticky after a single Remove: %s", f.Display(true))
+	}
+}
+
+func TestCountingBloomFilterErrors(t *testing.T) {
+	tests := []struct {
+		size        int
+		hashWeight  int
+		counterBits int
+		shouldErr   bool
+	}{
+		{-1, 4, 4, true},
+		{0, 4, 4, true},
+		{64, 1, 4, true},
+		{64, 4, 3, true},
+		{64, 4, 5, true},
+		{64, 4, 4, false},
+		{64, 4, 8, false},
+	}
+	for _, test := range tests {
+		_, err := bloom_filter.NewCountingBloomFilter(test.size, test.hashWeight, test.counterBits)
+		if test.shouldErr && err == nil {
+			t.Errorf("expected error for %+v but got none", test)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("unexpected error for %+v: %s", test, err)
+		}
+	}
+}
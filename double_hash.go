@@ -0,0 +1,34 @@
+package bloom_filter
+
+import (
+	"fmt"
+	"math"
+)
+
+const minK = 1
+
+var errKTooSmall = fmt.Errorf("k must be greater than %d", minK-1)
+
+// Constructs a BloomFilter with the specified size that hashes each element to
+// exactly k bit positions, using Kirsch-Mitzenmacher enhanced double hashing
+// instead of the Hashes registry. This gives direct control over k, the
+// standard knob in the false positive rate formula used by
+// ExpectedFalsePositiveRate, without being constrained to sums of registry
+// hash weights.
+func NewBloomFilterK(size int, k int) (f *BloomFilter, err error) {
+	if size < minSize {
+		return nil, errSizeTooSmall
+	}
+	if size > maxSize {
+		return nil, errSizeTooLarge
+	}
+	if k < minK {
+		return nil, errKTooSmall
+	}
+	f = &BloomFilter{
+		strategy: strategyDoubleHash,
+		k:        k,
+	}
+	f.bitset = make([]byte, int(math.Ceil(float64(size)/8)))
+	return f, nil
+}
@@ -0,0 +1,68 @@
+package bloom_filter_test
+
+import (
+	"testing"
+
+	"github.com/vdinovi/go/bloom_filter"
+)
+
+func TestBloomFilterKBasic(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilterK(256, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.HashWeight() != 5 {
+		t.Errorf("expected HashWeight() to report k=5, got %d", f.HashWeight())
+	}
+	for _, w := range []string{"abc", "def", "ghi"} {
+		b := []byte(w)
+		f.Add(b)
+		if !f.Query(b) {
+			t.Errorf("expected %q to be in set but was not: %s", w, f.Display(true))
+		}
+	}
+	for _, w := range []string{"jkl", "mno", "pqr"} {
+		b := []byte(w)
+		if f.Query(b) {
+			t.Errorf("didn't expect %q to be in set but was: %s", w, f.Display(true))
+		}
+	}
+}
+
+func TestBloomFilterKErrors(t *testing.T) {
+	tests := []struct {
+		size      int
+		k         int
+		shouldErr bool
+	}{
+		{-1, 5, true},
+		{0, 5, true},
+		{1, 5, false},
+		{1, 0, true},
+		{1, -1, true},
+		{1, 1, false},
+	}
+	for _, test := range tests {
+		_, err := bloom_filter.NewBloomFilterK(test.size, test.k)
+		if test.shouldErr && err == nil {
+			t.Errorf("expected error for %+v but got none", test)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("unexpected error for %+v: %s", test, err)
+		}
+	}
+}
+
+func TestBloomFilterKUnionIncompatibleWithRegistry(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilterK(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Union(g); err == nil {
+		t.Error("expected error unioning a double-hash filter with a registry-based filter but got none")
+	}
+}
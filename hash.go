@@ -40,6 +40,16 @@ var Hashes = [...]Hash{
 
 var errNotEnoughHashFunctions = fmt.Errorf("not enough available hash functions")
 
+// lookupHash resolves a hash by name against the Hashes registry.
+func lookupHash(name string) (Hash, bool) {
+	for _, h := range Hashes {
+		if h.Name == name {
+			return h, true
+		}
+	}
+	return Hash{}, false
+}
+
 func hashset(weight int) ([]Hash, error) {
 	hs := []Hash{}
 	w := weight
@@ -56,6 +66,24 @@ func hashset(weight int) ([]Hash, error) {
 	return hs, nil
 }
 
+// doubleHashPositions derives k bit positions for e in [0, m) using the
+// Kirsch-Mitzenmacher enhanced double hashing scheme: g_i(x) = (h1 + i*h2 + i*i) mod m,
+// where h1 and h2 are two independent 32-bit base hashes.
+//
+// m is taken as uint64 rather than uint32 because m may be exactly maxSize
+// (2^32), which overflows uint32; the resulting positions still fit in
+// uint32 since they're always < m.
+func doubleHashPositions(e []byte, k int, m uint64) []uint32 {
+	h1 := uint64(fnv1a_64(e)[0])
+	h2 := uint64(xxhash_64(e)[0])
+	positions := make([]uint32, k)
+	for i := 0; i < k; i += 1 {
+		ii := uint64(i)
+		positions[i] = uint32((h1 + ii*h2 + ii*ii) % m)
+	}
+	return positions
+}
+
 const (
 	djb264Start = 5381
 )
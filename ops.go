@@ -0,0 +1,99 @@
+package bloom_filter
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+var (
+	errNumBitsMismatch = fmt.Errorf("filters must have the same number of bits")
+	errHashsetMismatch = fmt.Errorf("filters must use the same hashes in the same order")
+)
+
+// Union merges other into f in place, such that querying f afterwards returns true
+// for anything that was previously in either f or other. Both filters must have the
+// same NumBits() and the same hashset (same names in the same order).
+func (f *BloomFilter) Union(other *BloomFilter) error {
+	if err := f.checkCompatible(other); err != nil {
+		return err
+	}
+	for i := range f.bitset {
+		f.bitset[i] |= other.bitset[i]
+	}
+	return nil
+}
+
+// Intersect narrows f in place to the elements it may have in common with other,
+// such that querying f afterwards may return true only for elements that were
+// previously in both f and other. Both filters must have the same NumBits() and
+// the same hashset (same names in the same order).
+func (f *BloomFilter) Intersect(other *BloomFilter) error {
+	if err := f.checkCompatible(other); err != nil {
+		return err
+	}
+	for i := range f.bitset {
+		f.bitset[i] &= other.bitset[i]
+	}
+	return nil
+}
+
+// Equals returns true if f and other have identical bitsets and hashsets.
+func (f *BloomFilter) Equals(other *BloomFilter) bool {
+	if err := f.checkCompatible(other); err != nil {
+		return false
+	}
+	for i := range f.bitset {
+		if f.bitset[i] != other.bitset[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ApproximateCount estimates the number of distinct elements that have been added
+// to f, using the Swamidass-Baldi estimator: given X = popcount of the bitset,
+// m = NumBits, k = HashWeight, the estimate is -(m/k) * ln(1 - X/m).
+func (f *BloomFilter) ApproximateCount() uint64 {
+	m := float64(f.NumBits())
+	k := float64(f.HashWeight())
+	x := float64(f.popcount())
+	if x >= m {
+		return 0
+	}
+	return uint64(-(m / k) * math.Log(1-x/m))
+}
+
+// checkCompatible verifies that f and other share the same NumBits and hashing
+// strategy (same hashset in the same order, or the same k for double hashing),
+// as required by Union, Intersect, and Equals.
+func (f *BloomFilter) checkCompatible(other *BloomFilter) error {
+	if f.NumBits() != other.NumBits() {
+		return errNumBitsMismatch
+	}
+	if f.strategy != other.strategy {
+		return errHashsetMismatch
+	}
+	if f.strategy == strategyDoubleHash {
+		if f.k != other.k {
+			return errHashsetMismatch
+		}
+		return nil
+	}
+	if len(f.hashset) != len(other.hashset) {
+		return errHashsetMismatch
+	}
+	for i, h := range f.hashset {
+		if h.Name != other.hashset[i].Name {
+			return errHashsetMismatch
+		}
+	}
+	return nil
+}
+
+func (f *BloomFilter) popcount() (count int) {
+	for _, b := range f.bitset {
+		count += bits.OnesCount8(b)
+	}
+	return count
+}
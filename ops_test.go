@@ -0,0 +1,102 @@
+package bloom_filter_test
+
+import (
+	"testing"
+
+	"github.com/vdinovi/go/bloom_filter"
+)
+
+func TestBloomFilterUnion(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("abc"))
+	g.Add([]byte("def"))
+
+	if err := f.Union(g); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !f.Query([]byte("abc")) || !f.Query([]byte("def")) {
+		t.Errorf("expected union to contain elements of both filters: %s", f.Display(true))
+	}
+}
+
+func TestBloomFilterIntersect(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("abc"))
+	f.Add([]byte("def"))
+	g.Add([]byte("abc"))
+
+	if err := f.Intersect(g); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !f.Query([]byte("abc")) {
+		t.Errorf("expected intersection to retain shared elements: %s", f.Display(true))
+	}
+}
+
+func TestBloomFilterUnionIntersectIncompatible(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := bloom_filter.NewBloomFilter(32, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Union(g); err == nil {
+		t.Error("expected error for mismatched NumBits but got none")
+	}
+	if err := f.Intersect(g); err == nil {
+		t.Error("expected error for mismatched NumBits but got none")
+	}
+}
+
+func TestBloomFilterEquals(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Equals(g) {
+		t.Errorf("expected two empty filters with identical params to be equal")
+	}
+	f.Add([]byte("abc"))
+	if f.Equals(g) {
+		t.Errorf("expected filters to differ after adding to only one")
+	}
+	g.Add([]byte("abc"))
+	if !f.Equals(g) {
+		t.Errorf("expected filters to be equal after adding the same element to both")
+	}
+}
+
+func TestBloomFilterApproximateCount(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(10000, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := []string{"abc", "def", "ghi", "jkl", "mno"}
+	for _, w := range words {
+		f.Add([]byte(w))
+	}
+	count := f.ApproximateCount()
+	if count < 1 || count > uint64(len(words)*2) {
+		t.Errorf("expected ApproximateCount near %d but got %d", len(words), count)
+	}
+}
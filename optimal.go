@@ -0,0 +1,44 @@
+package bloom_filter
+
+import (
+	"fmt"
+	"math"
+)
+
+var errExpectedItemsTooSmall = fmt.Errorf("expectedItems must be greater than 0")
+
+// OptimalNumBits returns the number of bits m that minimizes the false positive
+// rate for n expected items at target false positive rate p:
+//
+//	m = ceil(-n * ln(p) / (ln2)^2)
+func OptimalNumBits(n int, p float64) int {
+	return int(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+}
+
+// OptimalNumHashes returns the number of hash functions k that minimizes the
+// false positive rate for n expected items against m bits:
+//
+//	k = round((m/n) * ln2)
+func OptimalNumHashes(n, m int) int {
+	return int(math.Round(float64(m) / float64(n) * math.Ln2))
+}
+
+// Constructs a BloomFilter sized to hit targetFPR for expectedItems elements.
+// m and k are computed via OptimalNumBits and OptimalNumHashes (m rounded up to
+// a byte boundary), then delegated to NewBloomFilterK so that any computed k is
+// achievable rather than being constrained to sums of Hashes registry weights.
+func NewBloomFilterForFPR(expectedItems int, targetFPR float64) (*BloomFilter, error) {
+	if expectedItems < 1 {
+		return nil, errExpectedItemsTooSmall
+	}
+	if targetFPR <= 0 || targetFPR >= 1 {
+		return nil, errInvalidTargetFPR
+	}
+	m := OptimalNumBits(expectedItems, targetFPR)
+	m = int(math.Ceil(float64(m)/8)) * 8
+	k := OptimalNumHashes(expectedItems, m)
+	if k < minK {
+		k = minK
+	}
+	return NewBloomFilterK(m, k)
+}
@@ -0,0 +1,91 @@
+package bloom_filter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vdinovi/go/bloom_filter"
+)
+
+func TestOptimalNumBitsAndHashes(t *testing.T) {
+	n := 1000
+	p := 0.01
+	m := bloom_filter.OptimalNumBits(n, p)
+	if m <= 0 {
+		t.Fatalf("expected positive OptimalNumBits, got %d", m)
+	}
+	k := bloom_filter.OptimalNumHashes(n, m)
+	if k <= 0 {
+		t.Fatalf("expected positive OptimalNumHashes, got %d", k)
+	}
+	fpr := bloom_filter.ExpectedFalsePositiveRate(n, m, k)
+	if fpr > p*1.5 {
+		t.Errorf("expected optimal m=%d, k=%d to achieve close to target FPR %f, got %f", m, k, p, fpr)
+	}
+}
+
+func TestNewBloomFilterForFPR(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilterForFPR(1000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i += 1 {
+		f.Add([]byte{byte(i), byte(i >> 8)})
+	}
+	falsePositives := 0
+	for i := 1000; i < 11000; i += 1 {
+		if f.Query([]byte{byte(i), byte(i >> 8), 0xff}) {
+			falsePositives += 1
+		}
+	}
+	if rate := float64(falsePositives) / 10000; rate > 0.05 {
+		t.Errorf("expected false positive rate near 0.01, got %f (%d/10000)", rate, falsePositives)
+	}
+}
+
+func TestNewBloomFilterForFPRWriteReadRoundTrip(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilterForFPR(1000, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("abc"))
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	g := &bloom_filter.BloomFilter{}
+	if _, err := g.ReadFrom(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f.Display(true) != g.Display(true) {
+		t.Errorf("expected round-tripped filter to equal original: %s != %s", f, g)
+	}
+	if !g.Query([]byte("abc")) {
+		t.Errorf("expected round-tripped filter to still contain %q: %s", "abc", g.Display(true))
+	}
+}
+
+func TestNewBloomFilterForFPRErrors(t *testing.T) {
+	tests := []struct {
+		expectedItems int
+		targetFPR     float64
+		shouldErr     bool
+	}{
+		{1000, 0.01, false},
+		{0, 0.01, true},
+		{-1, 0.01, true},
+		{1000, 0, true},
+		{1000, 1, true},
+	}
+	for _, test := range tests {
+		_, err := bloom_filter.NewBloomFilterForFPR(test.expectedItems, test.targetFPR)
+		if test.shouldErr && err == nil {
+			t.Errorf("expected error for %+v but got none", test)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("unexpected error for %+v: %s", test, err)
+		}
+	}
+}
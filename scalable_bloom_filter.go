@@ -0,0 +1,140 @@
+package bloom_filter
+
+import (
+	"fmt"
+	"math"
+)
+
+var (
+	errInvalidTargetFPR       = fmt.Errorf("targetFPR must be in (0, 1)")
+	errInvalidGrowthFactor    = fmt.Errorf("growthFactor must be greater than 1")
+	errInvalidTighteningRatio = fmt.Errorf("tighteningRatio must be in (0, 1)")
+)
+
+// A Scalable Bloom Filter.
+// Wraps a growing sequence of inner BloomFilters so that callers don't need to
+// know the expected cardinality n up front, as NewBloomFilter requires.
+// See https://en.wikipedia.org/wiki/Bloom_filter#Scalable_Bloom_filters
+type ScalableBloomFilter struct {
+	filters []*BloomFilter
+	counts  []int // elements added to each filter, by index
+
+	initialSize     int
+	hashWeight      int
+	targetFPR       float64
+	growthFactor    float64
+	tighteningRatio float64
+}
+
+// Constructs a ScalableBloomFilter.
+//
+// initialSize and hashWeight parameterize the first inner filter exactly as
+// they would NewBloomFilter.
+//
+// targetFPR is the desired false positive rate of the first inner filter. Each
+// subsequent filter i tightens its own target to targetFPR * tighteningRatio^i,
+// which bounds the compound false positive rate across all filters by
+// targetFPR / (1 - tighteningRatio).
+//
+// growthFactor controls how much larger each successive filter is: the i-th
+// added filter (0-indexed, after the initial one) has size
+// initialSize * growthFactor^(i+1), sized to hit its tightened target FPR.
+func NewScalableBloomFilter(initialSize int, hashWeight int, targetFPR float64, growthFactor float64, tighteningRatio float64) (f *ScalableBloomFilter, err error) {
+	if targetFPR <= 0 || targetFPR >= 1 {
+		return nil, errInvalidTargetFPR
+	}
+	if growthFactor <= 1 {
+		return nil, errInvalidGrowthFactor
+	}
+	if tighteningRatio <= 0 || tighteningRatio >= 1 {
+		return nil, errInvalidTighteningRatio
+	}
+	first, err := NewBloomFilter(initialSize, hashWeight)
+	if err != nil {
+		return nil, err
+	}
+	f = &ScalableBloomFilter{
+		filters:         []*BloomFilter{first},
+		counts:          []int{0},
+		initialSize:     initialSize,
+		hashWeight:      hashWeight,
+		targetFPR:       targetFPR,
+		growthFactor:    growthFactor,
+		tighteningRatio: tighteningRatio,
+	}
+	return f, nil
+}
+
+// Returns the number of inner filters allocated so far.
+func (f *ScalableBloomFilter) NumFilters() int {
+	return len(f.filters)
+}
+
+// Adds an element to the set, inserting into the active (most recently
+// allocated) inner filter. If that filter's estimated fill has crossed its
+// tightened target FPR threshold, a new, larger filter is allocated and
+// becomes active before the next Add.
+func (f *ScalableBloomFilter) Add(e []byte) {
+	i := len(f.filters) - 1
+	f.filters[i].Add(e)
+	f.counts[i] += 1
+	if f.shouldGrow(i) {
+		f.grow(i)
+	}
+}
+
+// Queries for an element in the set.
+// Returns true if the element may, but is not necessarily, in the set.
+// Returns false if the element is not in the set.
+func (f *ScalableBloomFilter) Query(e []byte) bool {
+	for _, filter := range f.filters {
+		if filter.Query(e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *ScalableBloomFilter) String() string {
+	return fmt.Sprintf("ScalableBloomFilter{numFilters=%d, targetFPR=%f, growthFactor=%f, tighteningRatio=%f}",
+		len(f.filters), f.targetFPR, f.growthFactor, f.tighteningRatio)
+}
+
+// shouldGrow reports whether the i-th filter's estimated fill,
+// k*n/m, has exceeded -ln(1 - targetFPR_i), where targetFPR_i is that
+// filter's tightened target false positive rate.
+func (f *ScalableBloomFilter) shouldGrow(i int) bool {
+	active := f.filters[i]
+	k := float64(active.HashWeight())
+	m := float64(active.NumBits())
+	n := float64(f.counts[i])
+	fill := k * n / m
+	targetFPRi := f.targetFPR * math.Pow(f.tighteningRatio, float64(i))
+	threshold := -math.Log(1 - targetFPRi)
+	return fill > threshold
+}
+
+// maxGrowthSize caps how large a grown filter can get. It sits one byte
+// (8 bits) below maxSize.
+const maxGrowthSize = maxSize - 8
+
+// grow allocates the (i+1)-th inner filter, sized initialSize * growthFactor^(i+1)
+// and capped at maxGrowthSize, and makes it the active filter. If the active
+// filter is already at maxGrowthSize, growth is a no-op: Add keeps inserting
+// into it, accepting a higher compound false positive rate rather than
+// erroring or panicking.
+func (f *ScalableBloomFilter) grow(i int) {
+	size := int(math.Ceil(float64(f.initialSize) * math.Pow(f.growthFactor, float64(i+1))))
+	if size > maxGrowthSize || size < 0 {
+		size = maxGrowthSize
+	}
+	if size <= f.filters[i].NumBits() {
+		return
+	}
+	next, err := NewBloomFilter(size, f.hashWeight)
+	if err != nil {
+		return
+	}
+	f.filters = append(f.filters, next)
+	f.counts = append(f.counts, 0)
+}
@@ -0,0 +1,87 @@
+package bloom_filter_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vdinovi/go/bloom_filter"
+)
+
+func TestScalableBloomFilterBasic(t *testing.T) {
+	f, err := bloom_filter.NewScalableBloomFilter(256, 4, 0.01, 2, 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{"abc", "def", "ghi"} {
+		b := []byte(w)
+		f.Add(b)
+		if !f.Query(b) {
+			t.Errorf("expected %q to be in set but was not: %s", w, f)
+		}
+	}
+	for _, w := range []string{"jkl", "mno", "pqr"} {
+		b := []byte(w)
+		if f.Query(b) {
+			t.Errorf("didn't expect %q to be in set but was: %s", w, f)
+		}
+	}
+}
+
+func TestScalableBloomFilterGrows(t *testing.T) {
+	f, err := bloom_filter.NewScalableBloomFilter(1000, 4, 0.1, 2, 0.9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i += 1 {
+		f.Add([]byte(fmt.Sprintf("elem-%d", i)))
+	}
+	if f.NumFilters() <= 1 {
+		t.Errorf("expected filter to have grown past its initial size, but NumFilters()=%d", f.NumFilters())
+	}
+}
+
+func TestScalableBloomFilterGrowthCapsAtMaxSize(t *testing.T) {
+	// An extreme growthFactor pushes the requested filter size past maxSize
+	// on the very first growth, and a near-zero targetFPR keeps triggering
+	// growth after only a handful of elements. This should clamp to the growth
+	// cap (one byte below maxSize) and, once there, stop growing rather than
+	// panicking.
+	f, err := bloom_filter.NewScalableBloomFilter(1, 2, 1e-9, 1e10, 0.99)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i += 1 {
+		f.Add([]byte{byte(i)})
+	}
+	if f.NumFilters() != 2 {
+		t.Errorf("expected growth to stop once the active filter hit the growth cap, but NumFilters()=%d", f.NumFilters())
+	}
+}
+
+func TestScalableBloomFilterErrors(t *testing.T) {
+	tests := []struct {
+		initialSize     int
+		hashWeight      int
+		targetFPR       float64
+		growthFactor    float64
+		tighteningRatio float64
+		shouldErr       bool
+	}{
+		{8, 4, 0.01, 2, 0.5, false},
+		{8, 4, 0, 2, 0.5, true},
+		{8, 4, 1, 2, 0.5, true},
+		{8, 4, 0.01, 1, 0.5, true},
+		{8, 4, 0.01, 2, 0, true},
+		{8, 4, 0.01, 2, 1, true},
+		{-1, 4, 0.01, 2, 0.5, true},
+	}
+	for _, test := range tests {
+		_, err := bloom_filter.NewScalableBloomFilter(test.initialSize, test.hashWeight, test.targetFPR, test.growthFactor, test.tighteningRatio)
+		if test.shouldErr && err == nil {
+			t.Errorf("expected error for %+v but got none", test)
+		}
+		if !test.shouldErr && err != nil {
+			t.Errorf("unexpected error for %+v: %s", test, err)
+		}
+	}
+}
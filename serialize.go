@@ -0,0 +1,284 @@
+package bloom_filter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Wire format constants for the binary/JSON encodings of a BloomFilter.
+//
+// The binary layout is:
+//
+//	magic      [4]byte  "BLMF"
+//	version    uint8
+//	strategy   uint8  (0 = registry, 1 = double hashing; see hashStrategy)
+//	numBits    uint64
+//	hashWeight uint32
+//	if strategy == registry:
+//	    numHashes uint32
+//	    hashes    [numHashes]{ nameLen uint32, name []byte }
+//	if strategy == doubleHash:
+//	    k uint32
+//	bitset [ceil(numBits/8)]byte
+const (
+	binaryMagic   = "BLMF"
+	binaryVersion = uint8(2)
+)
+
+var (
+	errInvalidMagic       = fmt.Errorf("invalid magic bytes")
+	errUnsupportedVersion = fmt.Errorf("unsupported version")
+	errUnknownHashName    = fmt.Errorf("unknown hash name")
+	errHashWeightMismatch = fmt.Errorf("resolved hash weight does not match encoded hash weight")
+	errUnknownStrategy    = fmt.Errorf("unknown hash strategy")
+	errNumBitsTooLarge    = fmt.Errorf("numBits must be less than %d", uint64(maxSize)+1)
+)
+
+// WriteTo encodes f in the binary wire format and writes it to w.
+// It satisfies io.WriterTo.
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	buf.WriteString(binaryMagic)
+	buf.WriteByte(binaryVersion)
+	buf.WriteByte(byte(f.strategy))
+	if err := binary.Write(&buf, binary.BigEndian, uint64(f.NumBits())); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(&buf, binary.BigEndian, uint32(f.HashWeight())); err != nil {
+		return 0, err
+	}
+	switch f.strategy {
+	case strategyDoubleHash:
+		if err := binary.Write(&buf, binary.BigEndian, uint32(f.k)); err != nil {
+			return 0, err
+		}
+	default:
+		if err := binary.Write(&buf, binary.BigEndian, uint32(len(f.hashset))); err != nil {
+			return 0, err
+		}
+		for _, h := range f.hashset {
+			name := []byte(h.Name)
+			if err := binary.Write(&buf, binary.BigEndian, uint32(len(name))); err != nil {
+				return 0, err
+			}
+			buf.Write(name)
+		}
+	}
+	buf.Write(f.bitset)
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// ReadFrom decodes a BloomFilter from the binary wire format produced by WriteTo,
+// replacing f's contents. For a registry-strategy filter, each hash name is
+// re-resolved against the Hashes registry; an unknown name, or a resolved weight
+// that doesn't match the encoded hashWeight, is rejected. For a double-hash
+// strategy filter, the encoded k is rejected if it doesn't match hashWeight.
+// It satisfies io.ReaderFrom.
+func (f *BloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	readFull := func(buf []byte) error {
+		n, err := io.ReadFull(r, buf)
+		total += int64(n)
+		return err
+	}
+
+	magic := make([]byte, len(binaryMagic))
+	if err := readFull(magic); err != nil {
+		return total, err
+	}
+	if string(magic) != binaryMagic {
+		return total, errInvalidMagic
+	}
+
+	version := make([]byte, 1)
+	if err := readFull(version); err != nil {
+		return total, err
+	}
+	if version[0] != binaryVersion {
+		return total, errUnsupportedVersion
+	}
+
+	strategyBuf := make([]byte, 1)
+	if err := readFull(strategyBuf); err != nil {
+		return total, err
+	}
+	strategy := hashStrategy(strategyBuf[0])
+
+	numBitsBuf := make([]byte, 8)
+	if err := readFull(numBitsBuf); err != nil {
+		return total, err
+	}
+	numBits := binary.BigEndian.Uint64(numBitsBuf)
+
+	hashWeightBuf := make([]byte, 4)
+	if err := readFull(hashWeightBuf); err != nil {
+		return total, err
+	}
+	hashWeight := binary.BigEndian.Uint32(hashWeightBuf)
+
+	var hashset []Hash
+	var k int
+	switch strategy {
+	case strategyDoubleHash:
+		kBuf := make([]byte, 4)
+		if err := readFull(kBuf); err != nil {
+			return total, err
+		}
+		decodedK := binary.BigEndian.Uint32(kBuf)
+		if decodedK != hashWeight {
+			return total, errHashWeightMismatch
+		}
+		k = int(decodedK)
+	case strategyRegistry:
+		numHashesBuf := make([]byte, 4)
+		if err := readFull(numHashesBuf); err != nil {
+			return total, err
+		}
+		numHashes := binary.BigEndian.Uint32(numHashesBuf)
+
+		hashset = make([]Hash, 0, numHashes)
+		var resolvedWeight uint32
+		for i := uint32(0); i < numHashes; i += 1 {
+			nameLenBuf := make([]byte, 4)
+			if err := readFull(nameLenBuf); err != nil {
+				return total, err
+			}
+			nameBuf := make([]byte, binary.BigEndian.Uint32(nameLenBuf))
+			if err := readFull(nameBuf); err != nil {
+				return total, err
+			}
+			name := string(nameBuf)
+			h, ok := lookupHash(name)
+			if !ok {
+				return total, fmt.Errorf("%w: %q", errUnknownHashName, name)
+			}
+			hashset = append(hashset, h)
+			resolvedWeight += uint32(h.Weight)
+		}
+		if resolvedWeight != hashWeight {
+			return total, errHashWeightMismatch
+		}
+	default:
+		return total, errUnknownStrategy
+	}
+
+	if numBits > uint64(maxSize) {
+		return total, errNumBitsTooLarge
+	}
+	bitset := make([]byte, (numBits+7)/8)
+	if err := readFull(bitset); err != nil {
+		return total, err
+	}
+
+	f.bitset = bitset
+	f.hashset = hashset
+	f.strategy = strategy
+	f.k = k
+	return total, nil
+}
+
+// MarshalBinary encodes f using the same wire format as WriteTo.
+func (f *BloomFilter) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes f using the same wire format as ReadFrom.
+func (f *BloomFilter) UnmarshalBinary(data []byte) error {
+	_, err := f.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// jsonBloomFilter is the JSON-facing representation of a BloomFilter.
+// Bitset is encoded as base64 by the standard []byte json.Marshaler. Hashes is
+// populated for a registry-strategy filter, K for a double-hash strategy one.
+type jsonBloomFilter struct {
+	Version    uint8    `json:"version"`
+	Strategy   uint8    `json:"strategy"`
+	NumBits    uint64   `json:"numBits"`
+	HashWeight uint32   `json:"hashWeight"`
+	Hashes     []string `json:"hashes,omitempty"`
+	K          uint32   `json:"k,omitempty"`
+	Bitset     []byte   `json:"bitset"`
+}
+
+// MarshalJSON encodes f as JSON using the same header fields as the binary format.
+func (f *BloomFilter) MarshalJSON() ([]byte, error) {
+	payload := jsonBloomFilter{
+		Version:    binaryVersion,
+		Strategy:   uint8(f.strategy),
+		NumBits:    uint64(f.NumBits()),
+		HashWeight: uint32(f.HashWeight()),
+		Bitset:     f.bitset,
+	}
+	switch f.strategy {
+	case strategyDoubleHash:
+		payload.K = uint32(f.k)
+	default:
+		names := make([]string, len(f.hashset))
+		for i, h := range f.hashset {
+			names[i] = h.Name
+		}
+		payload.Hashes = names
+	}
+	return json.Marshal(payload)
+}
+
+// UnmarshalJSON decodes f from the JSON format produced by MarshalJSON, replacing
+// f's contents. As with ReadFrom, a registry-strategy filter's hash names are
+// re-resolved against the Hashes registry and rejected if unknown or if the
+// resolved weight doesn't match, while a double-hash strategy filter's k is
+// rejected if it doesn't match hashWeight.
+func (f *BloomFilter) UnmarshalJSON(data []byte) error {
+	var payload jsonBloomFilter
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	if payload.Version != binaryVersion {
+		return errUnsupportedVersion
+	}
+
+	strategy := hashStrategy(payload.Strategy)
+	var hashset []Hash
+	var k int
+	switch strategy {
+	case strategyDoubleHash:
+		if payload.K != payload.HashWeight {
+			return errHashWeightMismatch
+		}
+		k = int(payload.K)
+	case strategyRegistry:
+		hashset = make([]Hash, 0, len(payload.Hashes))
+		var resolvedWeight uint32
+		for _, name := range payload.Hashes {
+			h, ok := lookupHash(name)
+			if !ok {
+				return fmt.Errorf("%w: %q", errUnknownHashName, name)
+			}
+			hashset = append(hashset, h)
+			resolvedWeight += uint32(h.Weight)
+		}
+		if resolvedWeight != payload.HashWeight {
+			return errHashWeightMismatch
+		}
+	default:
+		return errUnknownStrategy
+	}
+
+	if payload.NumBits > uint64(maxSize) {
+		return errNumBitsTooLarge
+	}
+
+	f.bitset = payload.Bitset
+	f.hashset = hashset
+	f.strategy = strategy
+	f.k = k
+	return nil
+}
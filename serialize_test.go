@@ -0,0 +1,198 @@
+package bloom_filter_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/vdinovi/go/bloom_filter"
+)
+
+func TestBloomFilterWriteReadRoundTrip(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{"abc", "def", "ghi"} {
+		f.Add([]byte(w))
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	g := &bloom_filter.BloomFilter{}
+	if _, err := g.ReadFrom(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f.Display(true) != g.Display(true) {
+		t.Errorf("expected round-tripped filter to equal original: %s != %s", f, g)
+	}
+}
+
+func TestBloomFilterMarshalBinaryRoundTrip(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("abc"))
+
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	g := &bloom_filter.BloomFilter{}
+	if err := g.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f.Display(true) != g.Display(true) {
+		t.Errorf("expected round-tripped filter to equal original: %s != %s", f, g)
+	}
+}
+
+func TestBloomFilterMarshalJSONRoundTrip(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("abc"))
+
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	g := &bloom_filter.BloomFilter{}
+	if err := g.UnmarshalJSON(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f.Display(true) != g.Display(true) {
+		t.Errorf("expected round-tripped filter to equal original: %s != %s", f, g)
+	}
+}
+
+func TestBloomFilterKWriteReadRoundTrip(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilterK(1024, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{"abc", "def", "ghi"} {
+		f.Add([]byte(w))
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	g := &bloom_filter.BloomFilter{}
+	if _, err := g.ReadFrom(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f.Display(true) != g.Display(true) {
+		t.Errorf("expected round-tripped filter to equal original: %s != %s", f, g)
+	}
+	for _, w := range []string{"abc", "def", "ghi"} {
+		if !g.Query([]byte(w)) {
+			t.Errorf("expected round-tripped filter to still contain %q: %s", w, g.Display(true))
+		}
+	}
+	for _, w := range []string{"jkl", "mno", "pqr"} {
+		if g.Query([]byte(w)) {
+			t.Errorf("didn't expect round-tripped filter to contain %q: %s", w, g.Display(true))
+		}
+	}
+}
+
+func TestBloomFilterKMarshalJSONRoundTrip(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilterK(1024, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Add([]byte("abc"))
+
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	g := &bloom_filter.BloomFilter{}
+	if err := g.UnmarshalJSON(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if f.Display(true) != g.Display(true) {
+		t.Errorf("expected round-tripped filter to equal original: %s != %s", f, g)
+	}
+	if !g.Query([]byte("abc")) {
+		t.Errorf("expected round-tripped filter to still contain %q: %s", "abc", g.Display(true))
+	}
+}
+
+func TestBloomFilterReadFromUnknownHash(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Corrupt the first byte of the first hash name (just past its length
+	// prefix) so it no longer matches any registered hash.
+	data := buf.Bytes()
+	nameStart := 4 + 1 + 1 + 8 + 4 + 4 + 4 // magic+version+strategy+numBits+hashWeight+numHashes+nameLen
+	data[nameStart] ^= 0xff
+
+	g := &bloom_filter.BloomFilter{}
+	if _, err := g.ReadFrom(bytes.NewReader(data)); err == nil {
+		t.Errorf("expected error for unknown hash name but got none")
+	}
+}
+
+func TestBloomFilterReadFromNumBitsTooLarge(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := f.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Overwrite the numBits field with a value beyond maxSize so ReadFrom
+	// must reject it rather than attempting a huge allocation.
+	data := buf.Bytes()
+	numBitsStart := 4 + 1 + 1 // magic+version+strategy
+	binary.BigEndian.PutUint64(data[numBitsStart:numBitsStart+8], ^uint64(0))
+
+	g := &bloom_filter.BloomFilter{}
+	if _, err := g.ReadFrom(bytes.NewReader(data)); err == nil {
+		t.Errorf("expected error for numBits beyond maxSize but got none")
+	}
+}
+
+func TestBloomFilterUnmarshalJSONNumBitsTooLarge(t *testing.T) {
+	f, err := bloom_filter.NewBloomFilter(64, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	corrupted := bytes.Replace(data, []byte(`"numBits":64`), []byte(`"numBits":18446744073709551615`), 1)
+	if bytes.Equal(corrupted, data) {
+		t.Fatal("test setup error: numBits field not found in JSON payload")
+	}
+
+	g := &bloom_filter.BloomFilter{}
+	if err := g.UnmarshalJSON(corrupted); err == nil {
+		t.Errorf("expected error for numBits beyond maxSize but got none")
+	}
+}